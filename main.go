@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"github.com/DavidGamba/go-getoptions"
 	clr "github.com/logrusorgru/aurora"
+	"github.com/raspi/heksa/pkg/archive"
+	"github.com/raspi/heksa/pkg/encoder"
 	"github.com/raspi/heksa/pkg/iface"
 	"github.com/raspi/heksa/pkg/reader"
+	"github.com/raspi/heksa/pkg/regions"
+	"github.com/raspi/heksa/pkg/session"
 	"io"
 	"os"
 	"strconv"
@@ -20,10 +24,10 @@ const AUTHOR = `Pekka Järvinen`
 const HOMEPAGE = `https://github.com/raspi/heksa`
 
 // Parse command line arguments
-func getParams() (source iface.ReadSeekerCloser, displays []iface.CharacterFormatter, offsetViewer []iface.OffsetFormatter, limit uint64, startOffset int64, palette [256]clr.Color) {
+func getParams() (source iface.ReadSeekerCloser, displays []iface.CharacterFormatter, offsetViewer []iface.OffsetFormatter, limit uint64, startOffset int64, palette [256]clr.Color, outputMode string, regionsFile string, archiveMode string, fpath string, diffSource iface.ReadSeekerCloser, diffMode bool, diffOnly bool, decodeName string, recordFile string, replayFile string, speed float64, fileSize int64) {
 	opt := getoptions.New()
 
-	opt.HelpSynopsisArgs(`<filename>`)
+	opt.HelpSynopsisArgs(`<filename> [<filename2>]`)
 
 	opt.Bool(`help`, false,
 		opt.Alias("h", "?"),
@@ -58,6 +62,50 @@ func getParams() (source iface.ReadSeekerCloser, displays []iface.CharacterForma
 		opt.Description(`Start reading from certain offset. See NOTES.`),
 	)
 
+	argOutput := opt.StringOptional(`output`, `ansi`,
+		opt.Alias(`O`),
+		opt.ArgName(`mode`),
+		opt.Description(`Output mode: ansi, json, ndjson or cbor`),
+	)
+
+	argRegions := opt.StringOptional(`regions`, ``,
+		opt.ArgName(`file`),
+		opt.Description(`JSON or TSV region map; dump only the described regions of <filename> (requires a seekable file, not stdin)`),
+	)
+
+	argArchive := opt.StringOptional(`archive`, ``,
+		opt.ArgName(`tar|zip|auto`),
+		opt.Description(`Walk <filename> as an archive, dumping each member in place instead of the archive's raw bytes`),
+	)
+
+	opt.Bool(`diff`, false,
+		opt.Description(`Compare <filename> against <filename2>, showing both columns side by side. Honors the first value of --format/--offset-format`),
+	)
+
+	opt.Bool(`diff-only`, false,
+		opt.Description(`With --diff, only show rows where the two files differ`),
+	)
+
+	argDecode := opt.StringOptional(`decode`, ``,
+		opt.ArgName(`name`),
+		opt.Description(`Render an extra column via a pluggable decoder: utf8, ebcdic, cp437, x86 or arm64 (the last two are a minimal built-in decoder, not a full disassembler - see reader.RegisterDisassembler)`),
+	)
+
+	argRecord := opt.StringOptional(`record`, ``,
+		opt.ArgName(`file`),
+		opt.Description(`Record each row of the default ANSI hex dump with a timestamp to file, for replay or golden-file tests. Rows of --diff, --archive, --regions and structured --output modes are not recorded`),
+	)
+
+	argReplay := opt.StringOptional(`replay`, ``,
+		opt.ArgName(`file`),
+		opt.Description(`Replay a --record log to stdout, honoring the original inter-row delays, instead of reading <filename>`),
+	)
+
+	argSpeed := opt.StringOptional(`speed`, `1`,
+		opt.ArgName(`factor`),
+		opt.Description(`Playback speed multiplier for --replay (e.g. 2 = twice as fast)`),
+	)
+
 	remainingArgs, err := opt.Parse(os.Args[1:])
 
 	if opt.Called("help") {
@@ -75,6 +123,11 @@ func getParams() (source iface.ReadSeekerCloser, displays []iface.CharacterForma
 		fmt.Fprintf(os.Stdout, fmt.Sprintf(`    heksa -o no -f bit foo.dat`)+"\n")
 		fmt.Fprintf(os.Stdout, fmt.Sprintf(`    heksa -l 0x1024 foo.dat`)+"\n")
 		fmt.Fprintf(os.Stdout, fmt.Sprintf(`    heksa -s 0b1010 foo.dat`)+"\n")
+		fmt.Fprintf(os.Stdout, fmt.Sprintf(`    heksa -O ndjson foo.dat | jq .`)+"\n")
+		fmt.Fprintf(os.Stdout, fmt.Sprintf(`    heksa --diff --diff-only foo.dat bar.dat`)+"\n")
+		fmt.Fprintf(os.Stdout, fmt.Sprintf(`    heksa --decode utf8 foo.txt`)+"\n")
+		fmt.Fprintf(os.Stdout, fmt.Sprintf(`    heksa --record demo.log foo.dat`)+"\n")
+		fmt.Fprintf(os.Stdout, fmt.Sprintf(`    heksa --replay demo.log --speed 2`)+"\n")
 		os.Exit(0)
 	} else if opt.Called("version") {
 		fmt.Fprintf(os.Stdout, fmt.Sprintf(`%v build %v on %v`+"\n", VERSION, BUILD, BUILDDATE))
@@ -122,8 +175,26 @@ func getParams() (source iface.ReadSeekerCloser, displays []iface.CharacterForma
 		palette[i] = color
 	}
 
+	replayFile = *argReplay
+	recordFile = *argRecord
+
+	speed, err = strconv.ParseFloat(*argSpeed, 64)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error parsing speed: %v`, err))
+		os.Exit(1)
+	}
+
+	if replayFile != `` {
+		// Replay reads rows straight from the --record log, there's no
+		// source file to open.
+		return source, displays, offsetViewer, limit, startOffset, palette, *argOutput, *argRegions, *argArchive, fpath, diffSource, diffMode, diffOnly, *argDecode, recordFile, replayFile, speed, fileSize
+	}
+
+	diffMode = opt.Called(`diff`)
+	diffOnly = opt.Called(`diff-only`)
+
 	stat, _ := os.Stdin.Stat()
-	if (stat.Mode() & os.ModeCharDevice) == 0 {
+	if !diffMode && (stat.Mode()&os.ModeCharDevice) == 0 {
 		// Stdin has data
 		source = os.Stdin
 
@@ -132,45 +203,81 @@ func getParams() (source iface.ReadSeekerCloser, displays []iface.CharacterForma
 			offsetViewer[idx].SetFileSize(0)
 		}
 	} else {
-		// Read file
-		if len(remainingArgs) != 1 {
-			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: no file given as argument, see --help`))
-			os.Exit(1)
+		// Read file(s)
+		wantArgs := 1
+		if diffMode {
+			wantArgs = 2
 		}
 
-		fpath := remainingArgs[0]
-
-		fhandle, err := os.Open(fpath)
-		if err != nil {
-			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error opening file: %v`, err))
+		if len(remainingArgs) != wantArgs {
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: expected %d filename(s), see --help`, wantArgs))
 			os.Exit(1)
 		}
 
-		fi, err := fhandle.Stat()
-		if err != nil {
-			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error stat'ing file: %v`, err))
-			os.Exit(1)
-		}
+		fpath = remainingArgs[0]
 
-		if fi.IsDir() {
-			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: %v is directory`, fpath))
-			os.Exit(1)
-		}
+		fhandle, fi := openRegularFile(fpath)
 
 		// Hint offset viewer
 		for idx, _ := range offsetViewer {
 			offsetViewer[idx].SetFileSize(fi.Size())
 		}
 
+		fileSize = fi.Size()
 		source = fhandle
 
+		if diffMode {
+			dhandle, _ := openRegularFile(remainingArgs[1])
+			diffSource = dhandle
+		}
+	}
+
+	return source, displays, offsetViewer, limit, startOffset, palette, *argOutput, *argRegions, *argArchive, fpath, diffSource, diffMode, diffOnly, *argDecode, recordFile, replayFile, speed, fileSize
+}
+
+// openRegularFile opens path, verifies it's a regular file, and returns it
+// along with its os.FileInfo. It exits the process on any error.
+func openRegularFile(path string) (*os.File, os.FileInfo) {
+	fhandle, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error opening file: %v`, err))
+		os.Exit(1)
+	}
+
+	fi, err := fhandle.Stat()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error stat'ing file: %v`, err))
+		os.Exit(1)
 	}
 
-	return source, displays, offsetViewer, limit, startOffset, palette
+	if fi.IsDir() {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: %v is directory`, path))
+		os.Exit(1)
+	}
+
+	return fhandle, fi
 }
 
 func main() {
-	source, displays, offViewer, limit, startOffset, palette := getParams()
+	source, displays, offViewer, limit, startOffset, palette, outputMode, regionsFile, archiveMode, fpath, diffSource, diffMode, diffOnly, decodeName, recordFile, replayFile, speed, fileSize := getParams()
+
+	if replayFile != `` {
+		replay(replayFile, speed)
+		return
+	}
+
+	if diffMode {
+		dumpDiff(source, diffSource, offViewer, displays, palette, diffOnly)
+		source.Close()
+		diffSource.Close()
+		return
+	}
+
+	if archiveMode != `` {
+		dumpArchive(source, fpath, archiveMode, offViewer, displays, palette, limit, startOffset)
+		source.Close()
+		return
+	}
 
 	if startOffset != 0 {
 		// Seek to given offset
@@ -182,7 +289,41 @@ func main() {
 		}
 	}
 
-	r := reader.New(source, offViewer, displays, palette)
+	if regionsFile != `` {
+		dumpRegions(source, regionsFile, offViewer, displays, palette)
+		source.Close()
+		return
+	}
+
+	r := reader.New(source, offViewer, displays, palette, true, fileSize)
+
+	if decodeName != `` {
+		d, ok := reader.NewDecoder(decodeName)
+		if !ok {
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: unknown --decode value %q (known: %v)`, decodeName, reader.DecoderNames()))
+			os.Exit(1)
+		}
+
+		r.SetDecoder(d)
+	}
+
+	if outputMode != `ansi` {
+		dumpStructured(r, outputMode, limit)
+		source.Close()
+		return
+	}
+
+	var recorder *session.Recorder
+	if recordFile != `` {
+		rf, err := os.Create(recordFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error creating record file: %v`, err))
+			os.Exit(1)
+		}
+		defer rf.Close()
+
+		recorder = session.NewRecorder(rf)
+	}
 
 	// Dump hex
 	for {
@@ -198,6 +339,13 @@ func main() {
 
 		fmt.Println(s)
 
+		if recorder != nil {
+			if err := recorder.Record(s); err != nil {
+				fmt.Fprintln(os.Stderr, fmt.Sprintf(`error recording row: %v`, err))
+				os.Exit(1)
+			}
+		}
+
 		if limit > 0 && r.ReadBytes >= limit {
 			// Limit is set and found
 			break
@@ -208,3 +356,242 @@ func main() {
 	source.Close()
 
 }
+
+// replay reads a --record log from replayFile and prints each row to
+// stdout, honoring the original inter-row delays scaled by speed.
+func replay(replayFile string, speed float64) {
+	f, err := os.Open(replayFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error opening replay log: %v`, err))
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	rp := session.NewReplayer(f, speed)
+
+	for {
+		line, err := rp.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error replaying log: %v`, err))
+			os.Exit(1)
+		}
+
+		fmt.Println(line)
+	}
+}
+
+// dumpStructured emits rows through one of the pkg/encoder structured
+// formats (json, ndjson, cbor) instead of the ANSI renderer.
+func dumpStructured(r *reader.Reader, outputMode string, limit uint64) {
+	enc, err := encoder.New(outputMode, os.Stdout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: %v`, err))
+		os.Exit(1)
+	}
+
+	for {
+		row, err := r.ReadRow()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error while reading file: %v`, err))
+			os.Exit(1)
+		}
+
+		if err := enc.Encode(row); err != nil {
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error encoding row: %v`, err))
+			os.Exit(1)
+		}
+
+		if limit > 0 && r.ReadBytes >= limit {
+			// Limit is set and found
+			break
+		}
+	}
+
+	if err := enc.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error closing encoder: %v`, err))
+		os.Exit(1)
+	}
+}
+
+// dumpRegions reads the region map from mapFile and dumps source as a
+// series of labelled regions instead of one continuous stream.
+func dumpRegions(source iface.ReadSeekerCloser, mapFile string, offViewer []iface.OffsetFormatter, displays []iface.CharacterFormatter, palette [256]clr.Color) {
+	f, err := os.Open(mapFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error opening region map: %v`, err))
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	format := `tsv`
+	if strings.HasSuffix(strings.ToLower(mapFile), `.json`) {
+		format = `json`
+	}
+
+	regionList, err := regions.LoadMap(f, format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error loading region map: %v`, err))
+		os.Exit(1)
+	}
+
+	size, err := source.Seek(0, io.SeekEnd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`couldn't determine input size: %v`, err))
+		os.Exit(1)
+	}
+
+	if _, err := source.Seek(0, io.SeekStart); err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`couldn't seek: %v`, err))
+		os.Exit(1)
+	}
+
+	rr, err := reader.NewRegionReader(source, size, regionList, offViewer, displays, palette, false)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: %v`, err))
+		os.Exit(1)
+	}
+
+	for {
+		s, err := rr.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error while reading file: %v`, err))
+			os.Exit(1)
+		}
+
+		fmt.Println(s)
+	}
+}
+
+// dumpArchive walks fpath as a tar or zip archive, printing a banner and a
+// normal hex dump for each member. --limit and --seek apply per member.
+func dumpArchive(source iface.ReadSeekerCloser, fpath, archiveMode string, offViewer []iface.OffsetFormatter, displays []iface.CharacterFormatter, palette [256]clr.Color, limit uint64, startOffset int64) {
+	// os.Stdin (and any piped fd) is itself a *os.File, so a type assertion
+	// alone doesn't catch it; check the file's mode instead.
+	fhandle, ok := source.(*os.File)
+	if !ok {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: --archive requires a regular file, not stdin`))
+		os.Exit(1)
+	}
+
+	fi, err := fhandle.Stat()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error stat'ing file: %v`, err))
+		os.Exit(1)
+	}
+
+	if !fi.Mode().IsRegular() {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error: --archive requires a regular file, not stdin`))
+		os.Exit(1)
+	}
+
+	walker, err := archive.New(archiveMode, fpath, fhandle, fi.Size())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`error opening archive: %v`, err))
+		os.Exit(1)
+	}
+
+	for {
+		member, err := walker.Next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error walking archive: %v`, err))
+			os.Exit(1)
+		}
+
+		fmt.Println(fmt.Sprintf(`=== %s (%d bytes, mode %s, mtime %s) ===`, member.Name, member.Size, member.Mode, member.ModTime.Format(`2006-01-02T15:04:05Z07:00`)))
+
+		// Re-hint offset viewer with this member's size so percent/padding
+		// stays correct.
+		for idx := range offViewer {
+			offViewer[idx].SetFileSize(member.Size)
+		}
+
+		if startOffset != 0 {
+			if _, err := member.Source.Seek(startOffset, io.SeekCurrent); err != nil {
+				fmt.Fprintln(os.Stderr, fmt.Sprintf(`couldn't seek in %v: %v`, member.Name, err))
+				os.Exit(1)
+			}
+		}
+
+		r := reader.New(member.Source, offViewer, displays, palette, false, member.Size)
+
+		for {
+			s, err := r.Read()
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+
+				fmt.Fprintln(os.Stderr, fmt.Sprintf(`error while reading %v: %v`, member.Name, err))
+				os.Exit(1)
+			}
+
+			fmt.Println(s)
+
+			if limit > 0 && r.ReadBytes >= limit {
+				break
+			}
+		}
+
+		member.Source.Close()
+		fmt.Println()
+	}
+}
+
+// dumpDiff compares left and right 16 bytes at a time, showing both
+// columns side by side, formatted per the first entry of offViewer and of
+// displays (see reader.NewDiffReader).
+func dumpDiff(left, right iface.ReadSeekerCloser, offViewer []iface.OffsetFormatter, displays []iface.CharacterFormatter, palette [256]clr.Color, diffOnly bool) {
+	leftSize, err := left.Seek(0, io.SeekEnd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`couldn't determine size: %v`, err))
+		os.Exit(1)
+	}
+
+	rightSize, err := right.Seek(0, io.SeekEnd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`couldn't determine size: %v`, err))
+		os.Exit(1)
+	}
+
+	if _, err := left.Seek(0, io.SeekStart); err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`couldn't seek: %v`, err))
+		os.Exit(1)
+	}
+
+	if _, err := right.Seek(0, io.SeekStart); err != nil {
+		fmt.Fprintln(os.Stderr, fmt.Sprintf(`couldn't seek: %v`, err))
+		os.Exit(1)
+	}
+
+	dr := reader.NewDiffReader(left, right, leftSize, rightSize, displays, offViewer, palette, diffOnly)
+
+	for {
+		s, err := dr.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+
+			fmt.Fprintln(os.Stderr, fmt.Sprintf(`error while diffing: %v`, err))
+			os.Exit(1)
+		}
+
+		fmt.Println(s)
+	}
+}
@@ -0,0 +1,97 @@
+// Package archive lets heksa iterate the members of a tar or zip file and
+// dump each one in place, instead of requiring the archive to be extracted
+// first.
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/raspi/heksa/pkg/iface"
+)
+
+// Member is one entry inside a tar or zip archive, wrapped so it can be fed
+// into reader.New like any other heksa source.
+type Member struct {
+	Name    string
+	Size    int64
+	Mode    os.FileMode
+	ModTime time.Time
+	Source  iface.ReadSeekerCloser
+}
+
+// Walker iterates the members of an archive one at a time. Next returns
+// io.EOF once every member has been returned.
+type Walker interface {
+	Next() (Member, error)
+}
+
+// New returns a Walker for the given kind (`tar`, `zip` or `auto`). For
+// `auto`, the kind is guessed from path's extension.
+func New(kind, path string, f *os.File, size int64) (Walker, error) {
+	if kind == `auto` {
+		kind = detect(path)
+	}
+
+	switch kind {
+	case `tar`:
+		return newTarWalker(f), nil
+	case `targz`:
+		return newGzipTarWalker(f)
+	case `zip`:
+		return newZipWalker(f, size)
+	default:
+		return nil, fmt.Errorf(`unknown archive kind: %q`, kind)
+	}
+}
+
+// detect guesses the archive kind from path's extension. `.tar.gz`/`.tgz`
+// are reported as `targz`, a gzip-wrapped tar, distinct from a plain `tar`
+// stream.
+func detect(path string) string {
+	switch {
+	case hasSuffixFold(path, `.zip`):
+		return `zip`
+	case hasSuffixFold(path, `.tar.gz`), hasSuffixFold(path, `.tgz`):
+		return `targz`
+	default:
+		return `tar`
+	}
+}
+
+func hasSuffixFold(s, suffix string) bool {
+	if len(s) < len(suffix) {
+		return false
+	}
+
+	a, b := s[len(s)-len(suffix):], suffix
+
+	for i := range a {
+		ca, cb := a[i], b[i]
+		if 'A' <= ca && ca <= 'Z' {
+			ca += 'a' - 'A'
+		}
+		if 'A' <= cb && cb <= 'Z' {
+			cb += 'a' - 'A'
+		}
+		if ca != cb {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newBufferedSource is the stream-only fallback for members that can't be
+// accessed randomly (every tar member, and compressed zip members): the
+// member is read fully into memory up front.
+func newBufferedSource(r io.Reader, size int64) (iface.ReadSeekerCloser, error) {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, fmt.Errorf(`buffering archive member: %w`, err)
+	}
+
+	return &readerAtSeeker{data: buf}, nil
+}
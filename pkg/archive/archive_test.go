@@ -0,0 +1,50 @@
+package archive
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{`foo.zip`, `zip`},
+		{`FOO.ZIP`, `zip`},
+		{`foo.tar`, `tar`},
+		{`foo.tar.gz`, `targz`},
+		{`foo.tgz`, `targz`},
+		{`FOO.TGZ`, `targz`},
+		{`foo.TAR.GZ`, `targz`},
+		{`foo`, `tar`},
+		{`foo.bin`, `tar`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := detect(tt.path); got != tt.want {
+				t.Errorf(`detect(%q) = %q, want %q`, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHasSuffixFold(t *testing.T) {
+	tests := []struct {
+		s, suffix string
+		want      bool
+	}{
+		{`foo.zip`, `.zip`, true},
+		{`foo.ZIP`, `.zip`, true},
+		{`foo.ZIP`, `.ZIP`, true},
+		{`foo.tar.gz`, `.tar.gz`, true},
+		{`foo.tar.gz`, `.TAR.GZ`, true},
+		{`foo.zip`, `.tar`, false},
+		{`zip`, `.zip`, false}, // shorter than suffix
+		{``, ``, true},
+	}
+
+	for _, tt := range tests {
+		if got := hasSuffixFold(tt.s, tt.suffix); got != tt.want {
+			t.Errorf(`hasSuffixFold(%q, %q) = %v, want %v`, tt.s, tt.suffix, got, tt.want)
+		}
+	}
+}
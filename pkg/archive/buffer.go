@@ -0,0 +1,43 @@
+package archive
+
+import (
+	"bytes"
+	"io"
+)
+
+// readerAtSeeker is a seekable, in-memory iface.ReadSeekerCloser backed by a
+// plain byte slice, used for archive members that can't be read with random
+// access directly from the archive (tar members, compressed zip members).
+type readerAtSeeker struct {
+	data []byte
+	r    bytes.Reader
+	init bool
+}
+
+func (b *readerAtSeeker) ensure() {
+	if !b.init {
+		b.r.Reset(b.data)
+		b.init = true
+	}
+}
+
+func (b *readerAtSeeker) Read(p []byte) (int, error) {
+	b.ensure()
+	return b.r.Read(p)
+}
+
+func (b *readerAtSeeker) Seek(offset int64, whence int) (int64, error) {
+	b.ensure()
+	return b.r.Seek(offset, whence)
+}
+
+func (b *readerAtSeeker) ReadAt(p []byte, off int64) (int, error) {
+	b.ensure()
+	return b.r.ReadAt(p, off)
+}
+
+func (b *readerAtSeeker) Close() error {
+	return nil
+}
+
+var _ io.ReadSeeker = (*readerAtSeeker)(nil)
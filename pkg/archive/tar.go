@@ -0,0 +1,44 @@
+package archive
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+)
+
+// tarWalker iterates tar members. archive/tar is stream-only, so every
+// member is buffered into memory as it's visited.
+type tarWalker struct {
+	tr *tar.Reader
+}
+
+// newTarWalker reads tar members from r, which may be a plain tar stream
+// or (via newGzipTarWalker) one already unwrapped from gzip.
+func newTarWalker(r io.Reader) *tarWalker {
+	return &tarWalker{tr: tar.NewReader(r)}
+}
+
+func (w *tarWalker) Next() (Member, error) {
+	hdr, err := w.tr.Next()
+	if err != nil {
+		return Member{}, err // io.EOF propagates as-is
+	}
+
+	if hdr.Typeflag != tar.TypeReg {
+		// Not a plain file (directory, symlink, ...): skip over it.
+		return w.Next()
+	}
+
+	source, err := newBufferedSource(w.tr, hdr.Size)
+	if err != nil {
+		return Member{}, err
+	}
+
+	return Member{
+		Name:    hdr.Name,
+		Size:    hdr.Size,
+		Mode:    os.FileMode(hdr.Mode),
+		ModTime: hdr.ModTime,
+		Source:  source,
+	}, nil
+}
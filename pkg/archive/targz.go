@@ -0,0 +1,18 @@
+package archive
+
+import (
+	"compress/gzip"
+	"fmt"
+	"os"
+)
+
+// newGzipTarWalker unwraps f as gzip before handing it to tar.NewReader, for
+// `.tar.gz`/`.tgz` archives.
+func newGzipTarWalker(f *os.File) (*tarWalker, error) {
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf(`opening gzip-compressed tar: %w`, err)
+	}
+
+	return newTarWalker(gz), nil
+}
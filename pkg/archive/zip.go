@@ -0,0 +1,79 @@
+package archive
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+
+	"github.com/raspi/heksa/pkg/iface"
+)
+
+// zipWalker iterates zip members. Unlike tar, zip has a central directory,
+// so stored (uncompressed) members can be wrapped in an io.SectionReader
+// for true random access; compressed members still have to be buffered,
+// since flate's decompressed stream isn't seekable.
+type zipWalker struct {
+	zr  *zip.Reader
+	src io.ReaderAt
+	idx int
+}
+
+func newZipWalker(f *os.File, size int64) (*zipWalker, error) {
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zipWalker{zr: zr, src: f}, nil
+}
+
+func (w *zipWalker) Next() (Member, error) {
+	if w.idx >= len(w.zr.File) {
+		return Member{}, io.EOF
+	}
+
+	f := w.zr.File[w.idx]
+	w.idx++
+
+	source, err := w.open(f)
+	if err != nil {
+		return Member{}, err
+	}
+
+	return Member{
+		Name:    f.Name,
+		Size:    int64(f.UncompressedSize64),
+		Mode:    f.Mode(),
+		ModTime: f.Modified,
+		Source:  source,
+	}, nil
+}
+
+func (w *zipWalker) open(f *zip.File) (iface.ReadSeekerCloser, error) {
+	if f.Method == zip.Store {
+		offset, err := f.DataOffset()
+		if err != nil {
+			return nil, err
+		}
+
+		return sectionReadSeekCloser{io.NewSectionReader(w.src, offset, int64(f.UncompressedSize64))}, nil
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return newBufferedSource(rc, int64(f.UncompressedSize64))
+}
+
+// sectionReadSeekCloser adapts an io.SectionReader (which has no Close) to
+// iface.ReadSeekerCloser.
+type sectionReadSeekCloser struct {
+	*io.SectionReader
+}
+
+func (sectionReadSeekCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,27 @@
+package encoder
+
+import (
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/raspi/heksa/pkg/reader"
+)
+
+// cborEncoder emits one CBOR-encoded Row per call, back to back on the
+// stream. It's a compact binary alternative to ndjson for pipelines that
+// don't need to be human-readable.
+type cborEncoder struct {
+	enc *cbor.Encoder
+}
+
+func newCBOREncoder(w io.Writer) *cborEncoder {
+	return &cborEncoder{enc: cbor.NewEncoder(w)}
+}
+
+func (e *cborEncoder) Encode(row reader.Row) error {
+	return e.enc.Encode(row)
+}
+
+func (e *cborEncoder) Close() error {
+	return nil
+}
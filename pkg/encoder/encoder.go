@@ -0,0 +1,36 @@
+// Package encoder renders reader.Row values as structured data instead of
+// ANSI text, so heksa's output can be piped into jq, other Go programs, or
+// any language-agnostic post-processor.
+package encoder
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/raspi/heksa/pkg/reader"
+)
+
+// Encoder writes Row values to an underlying stream in some structured
+// format. Close must be called once after the last Encode to flush any
+// trailing structure (e.g. closing a JSON array).
+type Encoder interface {
+	Encode(row reader.Row) error
+	Close() error
+}
+
+// Modes lists the --output/-O values accepted by New.
+var Modes = []string{`json`, `ndjson`, `cbor`}
+
+// New returns the Encoder for the given --output/-O mode.
+func New(mode string, w io.Writer) (Encoder, error) {
+	switch mode {
+	case `json`:
+		return newJSONEncoder(w), nil
+	case `ndjson`:
+		return newNDJSONEncoder(w), nil
+	case `cbor`:
+		return newCBOREncoder(w), nil
+	default:
+		return nil, fmt.Errorf(`unknown output mode: %q`, mode)
+	}
+}
@@ -0,0 +1,98 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/raspi/heksa/pkg/reader"
+)
+
+func testRow() reader.Row {
+	return reader.Row{
+		Offset: 16,
+		ASCII:  `ABC.............`,
+		Formats: reader.RowFormats{
+			Hex: `41 42 43`,
+			Dec: `065 066 067`,
+			Oct: `101 102 103`,
+			Bit: `01000001 01000010 01000011`,
+		},
+	}
+}
+
+func TestJSONEncoderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newJSONEncoder(&buf)
+
+	want := testRow()
+	if err := enc.Encode(want); err != nil {
+		t.Fatalf(`Encode() error = %v`, err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf(`Close() error = %v`, err)
+	}
+
+	var got []reader.Row
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf(`Unmarshal() error = %v`, err)
+	}
+
+	if len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Errorf(`round-trip = %+v, want [%+v]`, got, want)
+	}
+
+	if bytes.Contains(buf.Bytes(), []byte(`"bytes"`)) {
+		t.Errorf(`JSON output still contains a "bytes" key: %s`, buf.Bytes())
+	}
+}
+
+func TestNDJSONEncoderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newNDJSONEncoder(&buf)
+
+	rows := []reader.Row{testRow(), testRow()}
+	for _, row := range rows {
+		if err := enc.Encode(row); err != nil {
+			t.Fatalf(`Encode() error = %v`, err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf(`Close() error = %v`, err)
+	}
+
+	dec := json.NewDecoder(&buf)
+	for i, want := range rows {
+		var got reader.Row
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf(`Decode() row %d error = %v`, i, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf(`row %d = %+v, want %+v`, i, got, want)
+		}
+	}
+}
+
+func TestCBOREncoderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	enc := newCBOREncoder(&buf)
+
+	want := testRow()
+	if err := enc.Encode(want); err != nil {
+		t.Fatalf(`Encode() error = %v`, err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf(`Close() error = %v`, err)
+	}
+
+	var got reader.Row
+	if err := cbor.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf(`Unmarshal() error = %v`, err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf(`round-trip = %+v, want %+v`, got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package encoder
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/raspi/heksa/pkg/reader"
+)
+
+// jsonEncoder buffers every Row and emits them as a single JSON array on
+// Close, since a JSON array can't be streamed without knowing the row count
+// up front.
+type jsonEncoder struct {
+	w    io.Writer
+	rows []reader.Row
+}
+
+func newJSONEncoder(w io.Writer) *jsonEncoder {
+	return &jsonEncoder{w: w}
+}
+
+func (e *jsonEncoder) Encode(row reader.Row) error {
+	e.rows = append(e.rows, row)
+	return nil
+}
+
+func (e *jsonEncoder) Close() error {
+	if e.rows == nil {
+		// Empty input: emit [] rather than null.
+		e.rows = []reader.Row{}
+	}
+
+	out, err := json.Marshal(e.rows)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(out)
+	return err
+}
+
+// ndjsonEncoder emits one Row per line, so it can be streamed row by row
+// without buffering the whole dump in memory.
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func newNDJSONEncoder(w io.Writer) *ndjsonEncoder {
+	return &ndjsonEncoder{enc: json.NewEncoder(w)}
+}
+
+func (e *ndjsonEncoder) Encode(row reader.Row) error {
+	return e.enc.Encode(row)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package reader
+
+import "strings"
+
+// Decoder is a pluggable byte-level interpreter, added as a smaller-scoped
+// alternative to folding utf8/ebcdic/cp437/asm into the existing
+// ByteFormatter switch (hex/dec/oct/bit/ascii, selected via --format).
+// That would have meant turning ByteFormatter itself into an interface
+// shared with pkg/iface, which --format's callers (including every
+// existing --format value) would also need to move onto; Decoder instead
+// adds decoders as one extra trailing column via the separate --decode
+// flag, selected by name and registered with RegisterFormatter, so
+// third parties can plug in their own (e.g. disassembly backends)
+// without touching pkg/reader. You cannot pass utf8/ebcdic/cp437 to
+// --format; only --decode exposes them.
+type Decoder interface {
+	// Width returns the fixed number of bytes one Format call consumes, or
+	// 0 if it varies by data (e.g. UTF-8), in which case Len is consulted
+	// instead.
+	Width() int
+
+	// Len returns how many bytes at buf[pos:] the next Format call will
+	// consume. Only called when Width returns 0.
+	Len(buf []byte, pos int) int
+
+	// Format renders the decoded glyph(s) for buf[pos:pos+n] into out,
+	// where n is Width() or Len(buf, pos).
+	Format(buf []byte, pos int, out *strings.Builder)
+}
+
+var decoderRegistry = map[string]func() Decoder{}
+
+// RegisterFormatter registers a named Decoder factory, making it usable as
+// a --decode value.
+func RegisterFormatter(name string, factory func() Decoder) {
+	decoderRegistry[name] = factory
+}
+
+// NewDecoder returns a fresh Decoder instance for name, or false if name
+// isn't registered.
+func NewDecoder(name string) (Decoder, bool) {
+	factory, ok := decoderRegistry[name]
+	if !ok {
+		return nil, false
+	}
+
+	return factory(), true
+}
+
+// DecoderNames lists every registered --decode value, for --help and error
+// messages.
+func DecoderNames() []string {
+	names := make([]string, 0, len(decoderRegistry))
+	for name := range decoderRegistry {
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,74 @@
+package reader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raspi/heksa/pkg/color"
+)
+
+// Disassembler decodes a single instruction from the start of buf, per
+// architecture. It's the pluggable backend behind the x86/arm64 --decode
+// formatters. Minimal built-in backends for x86 and arm64 are registered
+// from init in decoder_asm_x86.go and decoder_asm_arm64.go; any other arch
+// is not a valid --decode value until something calls RegisterDisassembler
+// for it, rather than silently printing placeholder text.
+type Disassembler interface {
+	// Disassemble decodes one instruction at the start of buf and returns
+	// its mnemonic, its length in bytes, and whether the opcode was
+	// actually recognized. length is always >= 1, even when ok is false,
+	// so the caller can resync on the next byte.
+	Disassemble(buf []byte) (mnemonic string, length int, ok bool)
+}
+
+var disassemblers = map[string]Disassembler{}
+
+// RegisterDisassembler plugs a Disassembler backend in for arch (e.g.
+// "x86", "arm64"), and registers arch as a --decode formatter backed by
+// it. Without a call to RegisterDisassembler, arch is not offered by
+// DecoderNames/NewDecoder.
+func RegisterDisassembler(arch string, d Disassembler) {
+	disassemblers[arch] = d
+	RegisterFormatter(arch, func() Decoder { return &asmDecoder{arch: arch} })
+}
+
+// asmDecoder renders one decoded instruction mnemonic per Format call,
+// delegating the actual decoding to a registered Disassembler. Like
+// utf8Decoder, it drives its own color directly: Format writes a
+// color.SetForeground escape ahead of the glyph it emits, dimming
+// instructions the backend couldn't actually recognize instead of
+// rendering them the same as a confident decode.
+type asmDecoder struct {
+	arch string
+}
+
+func (d *asmDecoder) Width() int {
+	return 0 // variable-width: one instruction, length decided by Len
+}
+
+func (d *asmDecoder) Len(buf []byte, pos int) int {
+	_, n, _ := d.disassemble(buf[pos:])
+	return n
+}
+
+func (d *asmDecoder) Format(buf []byte, pos int, out *strings.Builder) {
+	mnemonic, _, ok := d.disassemble(buf[pos:])
+
+	if !ok {
+		out.WriteString(fmt.Sprintf(`%s%s`, color.SetForeground, color.AnsiColor{Color: color.ColorGrey35_585858}))
+	} else {
+		out.WriteString(fmt.Sprintf(`%s%s`, color.SetForeground, color.AnsiColor{Color: color.ColorGrey100_ffffff}))
+	}
+
+	out.WriteString(mnemonic)
+	out.WriteString(` `)
+}
+
+func (d *asmDecoder) disassemble(buf []byte) (string, int, bool) {
+	backend, ok := disassemblers[d.arch]
+	if !ok {
+		return fmt.Sprintf(`(%s)`, d.arch), 1, false
+	}
+
+	return backend.Disassemble(buf)
+}
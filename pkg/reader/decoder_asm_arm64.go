@@ -0,0 +1,31 @@
+package reader
+
+import "fmt"
+
+func init() {
+	RegisterDisassembler(`arm64`, arm64Disassembler{})
+}
+
+// arm64Disassembler is a minimal arm64 decoder: every A64 instruction is
+// exactly 4 bytes, so the length half of this is always correct. It only
+// recognizes a couple of the most common encodings (nop, ret) by their
+// exact bit pattern; everything else is reported as an unrecognized
+// 4-byte word rather than a guess at its mnemonic.
+type arm64Disassembler struct{}
+
+func (arm64Disassembler) Disassemble(buf []byte) (string, int, bool) {
+	if len(buf) < 4 {
+		return `?`, len(buf), false
+	}
+
+	word := uint32(buf[0]) | uint32(buf[1])<<8 | uint32(buf[2])<<16 | uint32(buf[3])<<24
+
+	switch word {
+	case 0xd503201f:
+		return `nop`, 4, true
+	case 0xd65f03c0:
+		return `ret`, 4, true
+	default:
+		return fmt.Sprintf(`.word 0x%08x`, word), 4, false
+	}
+}
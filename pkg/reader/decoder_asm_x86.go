@@ -0,0 +1,60 @@
+package reader
+
+import "fmt"
+
+func init() {
+	RegisterDisassembler(`x86`, x86Disassembler{})
+}
+
+// x86OpcodeLength is a tiny, deliberately incomplete one-byte-opcode
+// length table: no prefixes, no ModRM/SIB decoding, no two-byte (0F xx)
+// opcodes. It lists only the single-byte nop/ret/leave/int3/hlt opcodes;
+// x86Disassembler.Disassemble does not read it, and decodes those plus the
+// push/pop/mov-immediate family by itself.
+var x86OpcodeLength = map[byte]int{
+	0x90: 1, // nop
+	0xc3: 1, // ret
+	0xc9: 1, // leave
+	0xcc: 1, // int3
+	0xf4: 1, // hlt
+}
+
+// x86Disassembler is a minimal, honestly-incomplete x86 length decoder:
+// real mnemonics for a handful of common single-byte opcodes, a generic
+// "mov reg, imm32"/"push/pop reg" family, and an unrecognized 1-byte
+// fallback for everything else. It makes no attempt at prefixes, ModRM,
+// SIB or multi-byte opcodes.
+type x86Disassembler struct{}
+
+func (x86Disassembler) Disassemble(buf []byte) (string, int, bool) {
+	if len(buf) == 0 {
+		return `?`, 1, false
+	}
+
+	op := buf[0]
+
+	switch {
+	case op == 0x90:
+		return `nop`, 1, true
+	case op == 0xc3:
+		return `ret`, 1, true
+	case op == 0xc9:
+		return `leave`, 1, true
+	case op == 0xcc:
+		return `int3`, 1, true
+	case op == 0xf4:
+		return `hlt`, 1, true
+	case op >= 0x50 && op <= 0x57:
+		return fmt.Sprintf(`push r%d`, op-0x50), 1, true
+	case op >= 0x58 && op <= 0x5f:
+		return fmt.Sprintf(`pop r%d`, op-0x58), 1, true
+	case op >= 0xb8 && op <= 0xbf:
+		if len(buf) < 5 {
+			return fmt.Sprintf(`mov r%d, ?`, op-0xb8), 1, false
+		}
+		imm := uint32(buf[1]) | uint32(buf[2])<<8 | uint32(buf[3])<<16 | uint32(buf[4])<<24
+		return fmt.Sprintf(`mov r%d, 0x%x`, op-0xb8, imm), 5, true
+	default:
+		return fmt.Sprintf(`db 0x%02x`, op), 1, false
+	}
+}
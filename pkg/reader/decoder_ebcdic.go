@@ -0,0 +1,63 @@
+package reader
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	RegisterFormatter(`ebcdic`, func() Decoder { return ebcdicDecoder{} })
+}
+
+// ebcdicDecoder renders each byte through IBM code page 037, for reading
+// dumps of mainframe data.
+type ebcdicDecoder struct{}
+
+func (ebcdicDecoder) Width() int {
+	return 1
+}
+
+func (ebcdicDecoder) Len(buf []byte, pos int) int {
+	return 1
+}
+
+func (ebcdicDecoder) Format(buf []byte, pos int, out *strings.Builder) {
+	out.WriteString(fmt.Sprintf(`%c`, cp037[buf[pos]]))
+}
+
+// cp037 maps each byte value to its IBM code page 037 glyph. Unassigned /
+// control positions fall back to '.'.
+var cp037 = [256]rune{
+	0x40: ' ',
+	0x4B: '.', 0x4C: '<', 0x4D: '(', 0x4E: '+', 0x4F: '|',
+	0x50: '&', 0x5A: '!', 0x5B: '$', 0x5C: '*', 0x5D: ')', 0x5E: ';',
+	0x60: '-', 0x61: '/', 0x6B: ',', 0x6C: '%', 0x6D: '_', 0x6E: '>', 0x6F: '?',
+	0x7A: ':', 0x7B: '#', 0x7C: '@', 0x7D: '\'', 0x7E: '=', 0x7F: '"',
+	0x81: 'a', 0x82: 'b', 0x83: 'c', 0x84: 'd', 0x85: 'e', 0x86: 'f', 0x87: 'g', 0x88: 'h', 0x89: 'i',
+	0x91: 'j', 0x92: 'k', 0x93: 'l', 0x94: 'm', 0x95: 'n', 0x96: 'o', 0x97: 'p', 0x98: 'q', 0x99: 'r',
+	0xA2: 's', 0xA3: 't', 0xA4: 'u', 0xA5: 'v', 0xA6: 'w', 0xA7: 'x', 0xA8: 'y', 0xA9: 'z',
+	0xC1: 'A', 0xC2: 'B', 0xC3: 'C', 0xC4: 'D', 0xC5: 'E', 0xC6: 'F', 0xC7: 'G', 0xC8: 'H', 0xC9: 'I',
+	0xD1: 'J', 0xD2: 'K', 0xD3: 'L', 0xD4: 'M', 0xD5: 'N', 0xD6: 'O', 0xD7: 'P', 0xD8: 'Q', 0xD9: 'R',
+	0xE2: 'S', 0xE3: 'T', 0xE4: 'U', 0xE5: 'V', 0xE6: 'W', 0xE7: 'X', 0xE8: 'Y', 0xE9: 'Z',
+	0xF0: '0', 0xF1: '1', 0xF2: '2', 0xF3: '3', 0xF4: '4', 0xF5: '5', 0xF6: '6', 0xF7: '7', 0xF8: '8', 0xF9: '9',
+	0x41: ' ', 0x42: 'â', 0x43: 'ä', 0x44: 'à', 0x45: 'á', 0x46: 'ã', 0x47: 'å', 0x48: 'ç', 0x49: 'ñ', 0x4A: '¢',
+	0x51: 'é', 0x52: 'ê', 0x53: 'ë', 0x54: 'è', 0x55: 'í', 0x56: 'î', 0x57: 'ï', 0x58: 'ì', 0x59: 'ß', 0x5F: '¬',
+	0x62: 'Â', 0x63: 'Ä', 0x64: 'À', 0x65: 'Á', 0x66: 'Ã', 0x67: 'Å', 0x68: 'Ç', 0x69: 'Ñ', 0x6A: '¦',
+	0x70: 'ø', 0x71: 'É', 0x72: 'Ê', 0x73: 'Ë', 0x74: 'È', 0x75: 'Í', 0x76: 'Î', 0x77: 'Ï', 0x78: 'Ì', 0x79: '`',
+	0x80: 'Ø', 0x8A: '«', 0x8B: '»', 0x8C: 'ð', 0x8D: 'ý', 0x8E: 'þ', 0x8F: '±',
+	0x90: '°', 0x9A: 'ª', 0x9B: 'º', 0x9C: 'æ', 0x9D: '¸', 0x9E: 'Æ', 0x9F: '¤',
+	0xA0: 'µ', 0xA1: '~', 0xAA: '¡', 0xAB: '¿', 0xAC: 'Ð', 0xAD: 'Ý', 0xAE: 'Þ', 0xAF: '®',
+	0xB0: '^', 0xB1: '£', 0xB2: '¥', 0xB3: '·', 0xB4: '©', 0xB5: '§', 0xB6: '¶', 0xB7: '¼', 0xB8: '½', 0xB9: '¾', 0xBA: '[', 0xBB: ']', 0xBC: '¯', 0xBD: '¨', 0xBE: '´', 0xBF: '×',
+	0xC0: '{', 0xCA: '­', 0xCB: 'ô', 0xCC: 'ö', 0xCD: 'ò', 0xCE: 'ó', 0xCF: 'õ',
+	0xD0: '}', 0xDA: '¹', 0xDB: 'û', 0xDC: 'ü', 0xDD: 'ù', 0xDE: 'ú', 0xDF: 'ÿ',
+	0xE0: '\\', 0xE1: '÷', 0xEA: '²', 0xEB: 'Ô', 0xEC: 'Ö', 0xED: 'Ò', 0xEE: 'Ó', 0xEF: 'Õ',
+	0xFA: '³', 0xFB: 'Û', 0xFC: 'Ü', 0xFD: 'Ù', 0xFE: 'Ú',
+}
+
+func init() {
+	for i, r := range cp037 {
+		if r == 0 {
+			cp037[i] = '.'
+		}
+	}
+}
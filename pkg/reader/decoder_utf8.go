@@ -0,0 +1,48 @@
+package reader
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/raspi/heksa/pkg/color"
+)
+
+func init() {
+	RegisterFormatter(`utf8`, func() Decoder { return utf8Decoder{} })
+}
+
+// utf8Decoder decodes runes spanning up to utf8.UTFMax bytes, showing the
+// glyph once and dimming the continuation bytes, so multi-byte sequences
+// are visually distinguishable from single-byte ones.
+type utf8Decoder struct{}
+
+func (utf8Decoder) Width() int {
+	return 0 // variable-width, see Len
+}
+
+func (utf8Decoder) Len(buf []byte, pos int) int {
+	_, n := utf8.DecodeRune(buf[pos:])
+	if n <= 0 {
+		return 1
+	}
+
+	return n
+}
+
+func (utf8Decoder) Format(buf []byte, pos int, out *strings.Builder) {
+	r, n := utf8.DecodeRune(buf[pos:])
+
+	if r == utf8.RuneError && n <= 1 {
+		out.WriteString(fmt.Sprintf(`%s%s.`, color.SetForeground, color.AnsiColor{Color: color.ColorGrey35_585858}))
+		return
+	}
+
+	out.WriteString(fmt.Sprintf(`%s%s%c`, color.SetForeground, color.AnsiColor{Color: color.ColorGrey100_ffffff}, r))
+
+	for i := 1; i < n; i++ {
+		// Continuation bytes: dim, so the reader can see the cluster width
+		// without it fighting for attention with the decoded glyph.
+		out.WriteString(fmt.Sprintf(`%s%s·`, color.SetForeground, color.AnsiColor{Color: color.ColorGrey35_585858}))
+	}
+}
@@ -0,0 +1,44 @@
+package reader
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUTF8DecoderLenTruncatedAtRowBoundary(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  []byte
+		pos  int
+		want int
+	}{
+		{`ascii`, []byte(`A`), 0, 1},
+		{`complete 3-byte rune`, []byte(`€`), 0, 3},
+		{`lead byte of 2-byte rune cut off at end of buffer`, []byte{0xc2}, 0, 1},
+		{`lead byte of 3-byte rune cut off at end of buffer`, []byte{0xe2, 0x82}, 0, 1},
+		{`lone continuation byte`, []byte{0x80}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (utf8Decoder{}).Len(tt.buf, tt.pos); got != tt.want {
+				t.Errorf(`Len(%v, %d) = %d, want %d`, tt.buf, tt.pos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUTF8DecoderFormatTruncatedAtRowBoundary(t *testing.T) {
+	// A 3-byte rune (e.g. the Euro sign, 0xe2 0x82 0xac) with only its first
+	// byte present, as happens when a row/buffer ends mid-rune. Format must
+	// not panic or render a bogus glyph - it should fall back to the dimmed
+	// placeholder used for any other invalid byte.
+	buf := []byte{0xe2}
+
+	var out strings.Builder
+	utf8Decoder{}.Format(buf, 0, &out)
+
+	if !strings.HasSuffix(out.String(), `.`) {
+		t.Errorf(`Format(%v, 0, ...) = %q, want it to end in the invalid-byte placeholder "."`, buf, out.String())
+	}
+}
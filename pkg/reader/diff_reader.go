@@ -0,0 +1,233 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/raspi/heksa/pkg/color"
+	"github.com/raspi/heksa/pkg/iface"
+)
+
+// DiffReader dumps two sources side by side, 16 bytes at a time, aligned by
+// offset: a left column, a splitter, a right column, each rendered with
+// the configured ByteFormatter. Differing bytes are rendered in a
+// distinct palette entry; matching bytes are dimmed. When one side is
+// shorter, it's padded with the usual ‡ sentinels.
+//
+// Only the first entry of formatters/offsetFormatter is honored: a
+// second --format column per side, on top of two sides already being
+// side by side, would make rows unreadably wide, and DiffReader already
+// dedicates its own trailing columns to each side's percentage, leaving
+// no room for a second offset column either.
+type DiffReader struct {
+	left, right         iface.ReadSeekerCloser
+	leftSize, rightSize int64
+	diffOnly            bool
+	ReadBytes           uint64
+	offset              int64
+	sb                  strings.Builder
+
+	formatter       ByteFormatter
+	offsetFormatter []OffsetFormatter
+
+	diffColor, matchColor, missingColor string
+}
+
+// NewDiffReader returns a DiffReader comparing left against right.
+// diffOnly, when set, makes Read skip rows where every byte matches on
+// both sides. formatters[0] selects the byte rendering for both sides,
+// defaulting to ViewHex if formatters is empty; offsetFormatter[0]
+// selects the left-hand offset rendering, or hides the offset column
+// entirely if offsetFormatter is empty.
+func NewDiffReader(left, right iface.ReadSeekerCloser, leftSize, rightSize int64, formatters []ByteFormatter, offsetFormatter []OffsetFormatter, palette [256]color.AnsiColor, diffOnly bool) *DiffReader {
+	bf := ViewHex
+	if len(formatters) > 0 {
+		bf = formatters[0]
+	}
+
+	return &DiffReader{
+		left:            left,
+		right:           right,
+		leftSize:        leftSize,
+		rightSize:       rightSize,
+		diffOnly:        diffOnly,
+		formatter:       bf,
+		offsetFormatter: offsetFormatter,
+		diffColor:       fmt.Sprintf(`%s%s`, color.SetForeground, color.AnsiColor{Color: color.ColorRed1_ff0000}),
+		matchColor:      fmt.Sprintf(`%s%s`, color.SetForeground, color.AnsiColor{Color: color.ColorGrey35_585858}),
+		missingColor:    fmt.Sprintf(`%s%s`, color.SetForeground, color.AnsiColor{Color: color.ColorGrey35_585858}),
+	}
+}
+
+// readSide reads up to 16 bytes from r, treating an already-exhausted side
+// (one shorter than the other) as "no more data" rather than an error.
+func readSide(r iface.ReadSeekerCloser, size, offset int64) ([]byte, error) {
+	if offset >= size {
+		return nil, nil
+	}
+
+	tmp := make([]byte, 16)
+	rb, err := r.Read(tmp)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return tmp[:rb], nil
+}
+
+// Read returns the next diffed row. It returns io.EOF once both sides are
+// exhausted, skipping over matching rows first if diffOnly is set.
+func (dr *DiffReader) Read() (string, error) {
+	for {
+		if dr.offset >= dr.leftSize && dr.offset >= dr.rightSize {
+			return ``, io.EOF
+		}
+
+		left, err := readSide(dr.left, dr.leftSize, dr.offset)
+		if err != nil {
+			return ``, err
+		}
+
+		right, err := readSide(dr.right, dr.rightSize, dr.offset)
+		if err != nil {
+			return ``, err
+		}
+
+		offset := dr.offset
+		dr.offset += 16
+		dr.ReadBytes += uint64(len(left))
+
+		if dr.diffOnly && rowsEqual(left, right) {
+			continue
+		}
+
+		return dr.render(offset, left, right), nil
+	}
+}
+
+func rowsEqual(left, right []byte) bool {
+	if len(left) != len(right) {
+		return false
+	}
+
+	for i := range left {
+		if left[i] != right[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (dr *DiffReader) render(offset int64, left, right []byte) string {
+	dr.sb.Reset()
+	dr.sb.Grow(160)
+
+	if len(dr.offsetFormatter) > 0 {
+		dr.sb.WriteString(dr.formatOffset(offset))
+		dr.sb.WriteString(color.SetForeground)
+		dr.sb.WriteString(color.AnsiColor{Color: color.ColorGrey93_eeeeee}.String())
+		dr.sb.WriteString(`┊`)
+	}
+
+	dr.writeSide(left, right)
+	dr.sb.WriteString(`┊`)
+	dr.writeSide(right, left)
+
+	leftPct := percent(offset, dr.leftSize)
+	rightPct := percent(offset, dr.rightSize)
+	dr.sb.WriteString(fmt.Sprintf(`┊L%06.2f%% R%06.2f%%`, leftPct, rightPct))
+
+	return dr.sb.String()
+}
+
+// formatOffset renders offset per dr.offsetFormatter[0]. Only called when
+// that slice is non-empty.
+func (dr *DiffReader) formatOffset(offset int64) string {
+	switch dr.offsetFormatter[0] {
+	case OffsetDec:
+		return fmt.Sprintf(`%08d`, offset)
+	case OffsetOct:
+		return fmt.Sprintf(`%08o`, offset)
+	case OffsetPercent:
+		size := dr.leftSize
+		if dr.rightSize > size {
+			size = dr.rightSize
+		}
+		return fmt.Sprintf(`%07.3f%%`, percent(offset, size))
+	default: // OffsetHex, and anything else the flag doesn't recognize
+		return fmt.Sprintf(`%08x`, offset)
+	}
+}
+
+func (dr *DiffReader) writeSide(side, other []byte) {
+	for i := 0; i < 16; i++ {
+		if i == 8 {
+			dr.sb.WriteString(` `)
+		}
+
+		switch {
+		case i >= len(side):
+			dr.sb.WriteString(dr.missingColor)
+			dr.sb.WriteString(diffMissingGlyph(dr.formatter))
+		case i >= len(other) || side[i] != other[i]:
+			dr.sb.WriteString(dr.diffColor)
+			dr.sb.WriteString(diffByteText(dr.formatter, side[i]))
+		default:
+			dr.sb.WriteString(dr.matchColor)
+			dr.sb.WriteString(diffByteText(dr.formatter, side[i]))
+		}
+
+		if i < 15 {
+			dr.sb.WriteString(` `)
+		}
+	}
+}
+
+// diffByteText renders one byte under bf. It mirrors Reader.Read's
+// per-byte switch for the plain formatters (hex/dec/oct/bit/ascii);
+// ViewHexWithASCII/ViewDecWithASCII aren't supported here, since their
+// bracketed "[x]" decoration would roughly double each side's width on
+// top of already being side by side - bf falls back to ViewHex for them.
+func diffByteText(bf ByteFormatter, b byte) string {
+	switch bf {
+	case ViewDec:
+		return fmt.Sprintf(`%03d`, b)
+	case ViewOct:
+		return fmt.Sprintf(`%03o`, b)
+	case ViewBit:
+		return fmt.Sprintf(`%08b`, b)
+	case ViewASCII:
+		return fmt.Sprintf(`%c`, asciiByteToChar[b])
+	default:
+		return fmt.Sprintf(`%02x`, b)
+	}
+}
+
+// diffByteWidth returns how many characters diffByteText renders for bf,
+// so diffMissingGlyph can pad a missing byte to the same width.
+func diffByteWidth(bf ByteFormatter) int {
+	switch bf {
+	case ViewDec, ViewOct:
+		return 3
+	case ViewBit:
+		return 8
+	case ViewASCII:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func diffMissingGlyph(bf ByteFormatter) string {
+	return strings.Repeat(`‡`, diffByteWidth(bf))
+}
+
+func percent(offset, size int64) float64 {
+	if size <= 0 {
+		return 100
+	}
+
+	return (float64(offset) * 100.0) / float64(size)
+}
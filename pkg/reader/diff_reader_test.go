@@ -0,0 +1,67 @@
+package reader
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/raspi/heksa/pkg/color"
+)
+
+// fakeReadSeekCloser adapts a bytes.Reader to iface.ReadSeekerCloser for
+// tests that don't need an actual file on disk.
+type fakeReadSeekCloser struct {
+	*bytes.Reader
+}
+
+func (fakeReadSeekCloser) Close() error {
+	return nil
+}
+
+func newFakeSource(b []byte) fakeReadSeekCloser {
+	return fakeReadSeekCloser{bytes.NewReader(b)}
+}
+
+func TestDiffReaderPadsShorterSide(t *testing.T) {
+	left := []byte(`ABCDEFGHIJKLMNOP`) // 16 bytes, one full row
+	right := []byte(`ABCDEFGH`)        // 8 bytes, right side runs out mid-row
+
+	dr := NewDiffReader(newFakeSource(left), newFakeSource(right), int64(len(left)), int64(len(right)), []ByteFormatter{ViewHex}, nil, [256]color.AnsiColor{}, false)
+
+	row, err := dr.Read()
+	if err != nil {
+		t.Fatalf(`Read() error = %v`, err)
+	}
+
+	missing := diffMissingGlyph(ViewHex)
+	if !strings.Contains(row, missing) {
+		t.Errorf(`Read() = %q, want it to contain the missing-byte glyph %q for the padded tail of the short side`, row, missing)
+	}
+
+	if _, err := dr.Read(); err != io.EOF {
+		t.Errorf(`second Read() error = %v, want io.EOF`, err)
+	}
+}
+
+func TestDiffReaderDiffOnlySkipsMatchingRows(t *testing.T) {
+	same := bytes.Repeat([]byte{0x41}, 16)
+
+	dr := NewDiffReader(newFakeSource(same), newFakeSource(same), 16, 16, []ByteFormatter{ViewHex}, nil, [256]color.AnsiColor{}, true)
+
+	if _, err := dr.Read(); err != io.EOF {
+		t.Errorf(`Read() error = %v, want io.EOF since both sides are identical and diffOnly is set`, err)
+	}
+}
+
+func TestDiffMissingGlyphMatchesDiffByteTextWidth(t *testing.T) {
+	tests := []ByteFormatter{ViewHex, ViewDec, ViewOct, ViewBit, ViewASCII}
+
+	for _, bf := range tests {
+		wantRunes := len([]rune(diffByteText(bf, 0x41)))
+		gotRunes := len([]rune(diffMissingGlyph(bf)))
+		if gotRunes != wantRunes {
+			t.Errorf(`diffMissingGlyph(%v) is %d runes wide, want %d to match diffByteText`, bf, gotRunes, wantRunes)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"github.com/raspi/heksa/pkg/color"
 	"github.com/raspi/heksa/pkg/iface"
-	"io"
 	"strings"
 )
 
@@ -32,6 +31,13 @@ type Reader struct {
 	offsetFormatterFormat map[OffsetFormatter]string // Printf format
 	offsetFormatterWidth  map[OffsetFormatter]int    // How much padding width needed
 	colors                colors                     // Colors
+	decoder               Decoder                    // Optional pluggable --decode column, nil if unset
+}
+
+// SetDecoder attaches a pluggable Decoder (see RegisterFormatter) that's
+// rendered as an extra trailing column, after the --format columns.
+func (r *Reader) SetDecoder(d Decoder) {
+	r.decoder = d
 }
 
 func New(r iface.ReadSeekerCloser, offsetFormatter []OffsetFormatter, formatters []ByteFormatter, palette [256]color.AnsiColor, showHeader bool, filesize int64) *Reader {
@@ -147,13 +153,22 @@ func (r *Reader) getoffsetRight(offset int64) string {
 	return r.sb.String()
 }
 
-// Read reads 16 bytes and provides string to display
+// Read reads 16 bytes and provides string to display. It's an ANSI renderer
+// layered on top of ReadRow(), which does the actual reading.
 func (r *Reader) Read() (string, error) {
-	offset, err := r.r.Seek(0, io.SeekCurrent)
+	row, err := r.ReadRow()
 	if err != nil {
 		return ``, err
 	}
 
+	offset := row.Offset
+	rb := len(row.Bytes)
+
+	// Keep tmp a full 16 bytes: the padding branch below reads tmp[i] for
+	// i >= rb when the last row is short.
+	tmp := make([]byte, 16)
+	copy(tmp, row.Bytes)
+
 	offsetLeft := r.getoffsetLeft(offset)
 	offsetRight := r.getoffsetRight(offset)
 	r.sb.Reset()
@@ -161,14 +176,6 @@ func (r *Reader) Read() (string, error) {
 
 	r.sb.WriteString(offsetLeft)
 
-	tmp := make([]byte, 16)
-	rb, err := r.r.Read(tmp)
-	if err != nil {
-		return ``, err
-	}
-
-	r.ReadBytes += uint64(rb)
-
 	// iterate through every formatter which outputs it's own format
 	for didx, byteFormatterType := range r.charFormatters {
 
@@ -260,11 +267,38 @@ func (r *Reader) Read() (string, error) {
 		}
 	}
 
+	if r.decoder != nil {
+		r.sb.WriteString(r.colors.splitterBreak)
+		r.sb.WriteString(r.Splitter)
+		r.writeDecoded(row.Bytes)
+	}
+
 	r.sb.WriteString(offsetRight)
 
 	return r.sb.String(), nil
 }
 
+// writeDecoded renders the optional --decode column by repeatedly calling
+// r.decoder.Format over buf, advancing by Width() (fixed-width decoders)
+// or Len() (variable-width, e.g. UTF-8).
+func (r *Reader) writeDecoded(buf []byte) {
+	width := r.decoder.Width()
+
+	for pos := 0; pos < len(buf); {
+		n := width
+		if n <= 0 {
+			n = r.decoder.Len(buf, pos)
+		}
+
+		if n <= 0 {
+			n = 1
+		}
+
+		r.decoder.Format(buf, pos, &r.sb)
+		pos += n
+	}
+}
+
 func (r *Reader) offsetHeader(otype OffsetFormatter) string {
 	width := r.offsetFormatterWidth[otype]
 	return strings.Repeat(`_`, width)
@@ -0,0 +1,209 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/raspi/heksa/pkg/color"
+	"github.com/raspi/heksa/pkg/iface"
+	"github.com/raspi/heksa/pkg/regions"
+)
+
+// namedRegionColors maps a regions.Region.Color value to the palette
+// override it selects. Only a small, fixed set of names is supported -
+// there's no general hex/RGB parser here, just enough to let a region map
+// pick out a region visually (e.g. "red" for a suspicious section).
+var namedRegionColors = map[string]color.AnsiColor{
+	`red`:   {Color: color.ColorRed1_ff0000},
+	`grey`:  {Color: color.ColorGrey35_585858},
+	`gray`:  {Color: color.ColorGrey35_585858},
+	`white`: {Color: color.ColorGrey100_ffffff},
+}
+
+// sectionReadSeekCloser adapts an io.SectionReader (which has no Close) to
+// iface.ReadSeekerCloser so it can be fed into reader.New like any other
+// source.
+type sectionReadSeekCloser struct {
+	*io.SectionReader
+}
+
+func (sectionReadSeekCloser) Close() error {
+	return nil
+}
+
+type regionPhase int
+
+const (
+	phaseGap regionPhase = iota
+	phaseHeader
+	phaseRows
+	phaseTrailingGap
+	phaseDone
+)
+
+// RegionReader dumps only the bytes inside a sorted, non-overlapping list of
+// regions.Region: a header line per region, the region's bytes, and a
+// coalesced "<n bytes skipped>" marker for every gap in between (and, if the
+// last region doesn't reach EOF, for the remainder of the file).
+//
+// It needs random access to skip between regions, so the source must
+// support io.ReaderAt - streaming sources such as stdin are rejected in
+// NewRegionReader.
+type RegionReader struct {
+	src             io.ReaderAt
+	size            int64
+	regions         []regions.Region
+	offsetFormatter []OffsetFormatter
+	formatters      []ByteFormatter
+	palette         [256]color.AnsiColor
+	showHeader      bool
+
+	idx    int // index of the region currently being visited
+	inner  *Reader
+	remain int64
+	phase  regionPhase
+}
+
+// NewRegionReader validates regions against size and source, and returns a
+// RegionReader ready to be driven with Next().
+func NewRegionReader(source iface.ReadSeekerCloser, size int64, regionList []regions.Region, offsetFormatter []OffsetFormatter, formatters []ByteFormatter, palette [256]color.AnsiColor, showHeader bool) (*RegionReader, error) {
+	// Every *os.File, including os.Stdin and a piped fd, satisfies
+	// io.ReaderAt at the type level even though seeking on it may fail at
+	// runtime. Check that the underlying file is a regular file instead
+	// (getParams likewise inspects stdin's mode to detect piped input).
+	f, ok := source.(*os.File)
+	if !ok {
+		return nil, fmt.Errorf(`--regions requires random access to the input, streaming sources (e.g. stdin) aren't supported`)
+	}
+
+	if fi, err := f.Stat(); err != nil || !fi.Mode().IsRegular() {
+		return nil, fmt.Errorf(`--regions requires random access to the input, streaming sources (e.g. stdin) aren't supported`)
+	}
+
+	src := io.ReaderAt(f)
+
+	if err := regions.Validate(regionList); err != nil {
+		return nil, fmt.Errorf(`invalid region map: %w`, err)
+	}
+
+	for _, reg := range regionList {
+		if reg.End() > size {
+			return nil, fmt.Errorf(`region %q ends at %d, past end of input (%d bytes)`, reg.Label, reg.End(), size)
+		}
+
+		if reg.Color != `` {
+			if _, ok := namedRegionColors[strings.ToLower(reg.Color)]; !ok {
+				return nil, fmt.Errorf(`region %q: unknown color %q (known: red, grey, white)`, reg.Label, reg.Color)
+			}
+		}
+	}
+
+	return &RegionReader{
+		src:             src,
+		size:            size,
+		regions:         regionList,
+		offsetFormatter: offsetFormatter,
+		formatters:      formatters,
+		palette:         palette,
+		showHeader:      showHeader,
+		phase:           phaseGap,
+	}, nil
+}
+
+// regionPalette returns the palette to dump reg with: rr.palette, the
+// default, unless reg.Color names an override, in which case every entry
+// is replaced with that single color.
+func (rr *RegionReader) regionPalette(reg regions.Region) [256]color.AnsiColor {
+	if reg.Color == `` {
+		return rr.palette
+	}
+
+	c, ok := namedRegionColors[strings.ToLower(reg.Color)]
+	if !ok {
+		// Unreachable: NewRegionReader already rejected unknown names.
+		return rr.palette
+	}
+
+	var override [256]color.AnsiColor
+	for i := range override {
+		override[i] = c
+	}
+
+	return override
+}
+
+func (rr *RegionReader) prevEnd() int64 {
+	if rr.idx == 0 {
+		return 0
+	}
+
+	return rr.regions[rr.idx-1].End()
+}
+
+// Next returns the next line to print: a gap marker, a region header, or a
+// row of dumped bytes. It returns io.EOF once every region and the
+// trailing hole (if any) have been emitted.
+func (rr *RegionReader) Next() (string, error) {
+	for {
+		switch rr.phase {
+		case phaseGap:
+			if rr.idx >= len(rr.regions) {
+				rr.phase = phaseTrailingGap
+				continue
+			}
+
+			reg := rr.regions[rr.idx]
+			gapStart := rr.prevEnd()
+			gapLen := reg.Offset - gapStart
+
+			rr.phase = phaseHeader
+
+			if gapLen > 0 {
+				return fmt.Sprintf(`--- <%d bytes skipped> ---`, gapLen), nil
+			}
+
+			continue
+
+		case phaseHeader:
+			reg := rr.regions[rr.idx]
+			section := io.NewSectionReader(rr.src, reg.Offset, reg.Length)
+			rr.inner = New(sectionReadSeekCloser{section}, rr.offsetFormatter, rr.formatters, rr.regionPalette(reg), rr.showHeader, reg.Length)
+			rr.remain = reg.Length
+			rr.phase = phaseRows
+
+			return fmt.Sprintf(`--- %s (offset %d, %d bytes) ---`, reg.Label, reg.Offset, reg.Length), nil
+
+		case phaseRows:
+			if rr.remain <= 0 {
+				rr.inner = nil
+				rr.idx++
+				rr.phase = phaseGap
+				continue
+			}
+
+			s, err := rr.inner.Read()
+			if err != nil {
+				return ``, err
+			}
+
+			rr.remain -= 16
+
+			return s, nil
+
+		case phaseTrailingGap:
+			rr.phase = phaseDone
+
+			lastEnd := rr.prevEnd()
+			if lastEnd < rr.size {
+				return fmt.Sprintf(`--- <%d bytes skipped> ---`, rr.size-lastEnd), nil
+			}
+
+			continue
+
+		case phaseDone:
+			return ``, io.EOF
+		}
+	}
+}
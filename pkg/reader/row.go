@@ -0,0 +1,90 @@
+package reader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Row is a single, formatter-agnostic 16-byte slice of the input, decoupled
+// from the ANSI string building done in Read(). It's the building block for
+// the structured output encoders in pkg/encoder.
+type Row struct {
+	Offset int64  `json:"offset"` // Offset of the first byte in this row
+	Bytes  []byte `json:"-"`      // Raw bytes read (may be less than 16 on the last row); not part of the JSON/NDJSON wire format, see Formats.Hex
+	ASCII  string `json:"ascii"`  // Bytes rendered through the ASCII table
+
+	Formats RowFormats `json:"formats"` // Same bytes, pre-rendered in the other display formats
+}
+
+// RowFormats holds the same 16 bytes pre-rendered in the display formats
+// heksa already knows about, for consumers that don't want to reimplement
+// the padding/width rules themselves.
+type RowFormats struct {
+	Hex string `json:"hex"`
+	Dec string `json:"dec"`
+	Oct string `json:"oct"`
+	Bit string `json:"bit"`
+}
+
+// ReadRow reads the next 16 bytes and returns them as a Row, without doing
+// any ANSI/palette rendering. Read() builds on top of it.
+func (r *Reader) ReadRow() (Row, error) {
+	offset, err := r.r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Row{}, err
+	}
+
+	tmp := make([]byte, 16)
+	rb, err := r.r.Read(tmp)
+	if err != nil {
+		return Row{}, err
+	}
+
+	r.ReadBytes += uint64(rb)
+
+	tmp = tmp[:rb]
+
+	return Row{
+		Offset:  offset,
+		Bytes:   tmp,
+		ASCII:   rowASCII(tmp),
+		Formats: rowFormats(tmp),
+	}, nil
+}
+
+func rowASCII(b []byte) string {
+	var sb strings.Builder
+	for _, v := range b {
+		sb.WriteRune(asciiByteToChar[v])
+	}
+	return sb.String()
+}
+
+func rowFormats(b []byte) RowFormats {
+	return RowFormats{
+		Hex: formatRowBytes(ViewHex, b),
+		Dec: formatRowBytes(ViewDec, b),
+		Oct: formatRowBytes(ViewOct, b),
+		Bit: formatRowBytes(ViewBit, b),
+	}
+}
+
+func formatRowBytes(f ByteFormatter, b []byte) string {
+	parts := make([]string, len(b))
+
+	for i, v := range b {
+		switch f {
+		case ViewHex:
+			parts[i] = fmt.Sprintf(`%02x`, v)
+		case ViewDec:
+			parts[i] = fmt.Sprintf(`%03d`, v)
+		case ViewOct:
+			parts[i] = fmt.Sprintf(`%03o`, v)
+		case ViewBit:
+			parts[i] = fmt.Sprintf(`%08b`, v)
+		}
+	}
+
+	return strings.Join(parts, ` `)
+}
@@ -0,0 +1,91 @@
+package regions
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LoadMap parses a region map file, either a JSON array of Region or a TSV
+// file (`offset<TAB>length<TAB>name[<TAB>color]`, one region per line,
+// blank lines and lines starting with `#` are skipped), and validates it.
+func LoadMap(r io.Reader, format string) ([]Region, error) {
+	var regions []Region
+	var err error
+
+	switch format {
+	case `json`:
+		regions, err = loadJSONMap(r)
+	case `tsv`:
+		regions, err = loadTSVMap(r)
+	default:
+		return nil, fmt.Errorf(`unknown region map format: %q`, format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Validate(regions); err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
+
+func loadJSONMap(r io.Reader) ([]Region, error) {
+	var regions []Region
+
+	if err := json.NewDecoder(r).Decode(&regions); err != nil {
+		return nil, fmt.Errorf(`parsing JSON region map: %w`, err)
+	}
+
+	return regions, nil
+}
+
+func loadTSVMap(r io.Reader) ([]Region, error) {
+	var regions []Region
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+
+	for scanner.Scan() {
+		lineNo++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == `` || strings.HasPrefix(line, `#`) {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 3 {
+			return nil, fmt.Errorf(`line %d: expected at least offset, length and name`, lineNo)
+		}
+
+		offset, err := strconv.ParseInt(fields[0], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf(`line %d: parsing offset: %w`, lineNo, err)
+		}
+
+		length, err := strconv.ParseInt(fields[1], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf(`line %d: parsing length: %w`, lineNo, err)
+		}
+
+		reg := Region{Offset: offset, Length: length, Label: fields[2]}
+		if len(fields) > 3 {
+			reg.Color = fields[3]
+		}
+
+		regions = append(regions, reg)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return regions, nil
+}
@@ -0,0 +1,41 @@
+// Package regions lets heksa dump a file as a set of labelled, meaningful
+// byte ranges (e.g. ELF/PE sections, or sprites inside a .dat/.dc6 file)
+// instead of one continuous stream, borrowing the sparseDatas/sparseHoles
+// idea from archive/tar.
+package regions
+
+import (
+	"fmt"
+)
+
+// Region is one labelled byte range of interest within a dumped file.
+type Region struct {
+	Offset int64  `json:"offset"`
+	Length int64  `json:"length"`
+	Label  string `json:"name"`
+	Color  string `json:"color,omitempty"` // Palette override (red, grey/gray or white), empty = use the default palette; see reader.RegionReader
+}
+
+// End returns the offset just past the last byte in the region.
+func (reg Region) End() int64 {
+	return reg.Offset + reg.Length
+}
+
+// Validate checks that regions are sorted by Offset and don't overlap.
+func Validate(regions []Region) error {
+	var prevEnd int64 = -1
+
+	for idx, reg := range regions {
+		if reg.Length <= 0 {
+			return fmt.Errorf(`region %d (%q): length must be > 0`, idx, reg.Label)
+		}
+
+		if reg.Offset < prevEnd {
+			return fmt.Errorf(`region %d (%q): starts at %d, which is before the previous region ends at %d`, idx, reg.Label, reg.Offset, prevEnd)
+		}
+
+		prevEnd = reg.End()
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package regions
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		regions []Region
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			regions: nil,
+			wantErr: false,
+		},
+		{
+			name: "sorted, non-overlapping",
+			regions: []Region{
+				{Offset: 0, Length: 4, Label: "a"},
+				{Offset: 4, Length: 4, Label: "b"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "gap between regions is fine",
+			regions: []Region{
+				{Offset: 0, Length: 4, Label: "a"},
+				{Offset: 10, Length: 4, Label: "b"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "overlapping regions",
+			regions: []Region{
+				{Offset: 0, Length: 4, Label: "a"},
+				{Offset: 2, Length: 4, Label: "b"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "out of order",
+			regions: []Region{
+				{Offset: 4, Length: 4, Label: "a"},
+				{Offset: 0, Length: 4, Label: "b"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero length",
+			regions: []Region{
+				{Offset: 0, Length: 0, Label: "a"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative length",
+			regions: []Region{
+				{Offset: 0, Length: -1, Label: "a"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.regions)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf(`Validate(%v) error = %v, wantErr %v`, tt.regions, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegionEnd(t *testing.T) {
+	reg := Region{Offset: 10, Length: 5}
+	if got := reg.End(); got != 15 {
+		t.Fatalf(`End() = %d, want 15`, got)
+	}
+}
+
+func TestLoadMapJSON(t *testing.T) {
+	const in = `[{"offset":0,"length":4,"name":"a"},{"offset":4,"length":4,"name":"b","color":"red"}]`
+
+	regions, err := LoadMap(strings.NewReader(in), `json`)
+	if err != nil {
+		t.Fatalf(`LoadMap: %v`, err)
+	}
+
+	want := []Region{
+		{Offset: 0, Length: 4, Label: "a"},
+		{Offset: 4, Length: 4, Label: "b", Color: "red"},
+	}
+
+	if len(regions) != len(want) {
+		t.Fatalf(`got %d regions, want %d`, len(regions), len(want))
+	}
+
+	for i := range want {
+		if regions[i] != want[i] {
+			t.Errorf(`region %d = %+v, want %+v`, i, regions[i], want[i])
+		}
+	}
+}
+
+func TestLoadMapJSONInvalid(t *testing.T) {
+	if _, err := LoadMap(strings.NewReader(`not json`), `json`); err == nil {
+		t.Fatal(`expected error for invalid JSON, got nil`)
+	}
+}
+
+func TestLoadMapTSV(t *testing.T) {
+	const in = "# comment\n\n0\t4\ta\n4\t4\tb\tred\n"
+
+	regions, err := LoadMap(strings.NewReader(in), `tsv`)
+	if err != nil {
+		t.Fatalf(`LoadMap: %v`, err)
+	}
+
+	want := []Region{
+		{Offset: 0, Length: 4, Label: "a"},
+		{Offset: 4, Length: 4, Label: "b", Color: "red"},
+	}
+
+	if len(regions) != len(want) {
+		t.Fatalf(`got %d regions, want %d`, len(regions), len(want))
+	}
+
+	for i := range want {
+		if regions[i] != want[i] {
+			t.Errorf(`region %d = %+v, want %+v`, i, regions[i], want[i])
+		}
+	}
+}
+
+func TestLoadMapTSVShortLine(t *testing.T) {
+	if _, err := LoadMap(strings.NewReader("0\t4\n"), `tsv`); err == nil {
+		t.Fatal(`expected error for line missing fields, got nil`)
+	}
+}
+
+func TestLoadMapTSVBadOffset(t *testing.T) {
+	if _, err := LoadMap(strings.NewReader("nope\t4\ta\n"), `tsv`); err == nil {
+		t.Fatal(`expected error for unparsable offset, got nil`)
+	}
+}
+
+func TestLoadMapOverlapRejected(t *testing.T) {
+	const in = "0\t4\ta\n2\t4\tb\n"
+
+	if _, err := LoadMap(strings.NewReader(in), `tsv`); err == nil {
+		t.Fatal(`expected overlap error, got nil`)
+	}
+}
+
+func TestLoadMapUnknownFormat(t *testing.T) {
+	if _, err := LoadMap(strings.NewReader(``), `yaml`); err == nil {
+		t.Fatal(`expected error for unknown format, got nil`)
+	}
+}
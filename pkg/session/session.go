@@ -0,0 +1,85 @@
+// Package session records and replays heksa's emitted rows, so a dump can
+// be turned into a reproducible, asciinema-style demo, or into a
+// golden-file test for the colored output without needing a real file on
+// disk.
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// event is one recorded row, with the wall-clock time it was emitted at.
+type event struct {
+	TSNanos int64  `json:"ts_ns"`
+	Line    string `json:"line"`
+}
+
+// Recorder writes each row it sees, together with a timestamp, as a
+// newline-delimited JSON log.
+type Recorder struct {
+	enc *json.Encoder
+}
+
+// NewRecorder returns a Recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// Record appends line to the log, stamped with the current time.
+func (r *Recorder) Record(line string) error {
+	return r.enc.Encode(event{TSNanos: time.Now().UnixNano(), Line: line})
+}
+
+// Replayer reads a Recorder's log back and reproduces the original inter-
+// row delays (scaled by speed) on each call to Next.
+type Replayer struct {
+	scanner *bufio.Scanner
+	speed   float64
+	prevTS  int64
+	started bool
+}
+
+// NewReplayer returns a Replayer reading from r. speed scales the delay
+// between rows - 2 plays back twice as fast, 0.5 half as fast. speed <= 0
+// is treated as 1 (real time).
+func NewReplayer(r io.Reader, speed float64) *Replayer {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	return &Replayer{scanner: bufio.NewScanner(r), speed: speed}
+}
+
+// Next sleeps for the original delay since the previous row (scaled by
+// speed) and returns the next recorded line. It returns io.EOF once the
+// log is exhausted.
+func (p *Replayer) Next() (string, error) {
+	if !p.scanner.Scan() {
+		if err := p.scanner.Err(); err != nil {
+			return ``, err
+		}
+
+		return ``, io.EOF
+	}
+
+	var ev event
+	if err := json.Unmarshal(p.scanner.Bytes(), &ev); err != nil {
+		return ``, fmt.Errorf(`parsing recorded row: %w`, err)
+	}
+
+	if p.started {
+		delta := time.Duration(ev.TSNanos - p.prevTS)
+		if delta > 0 {
+			time.Sleep(time.Duration(float64(delta) / p.speed))
+		}
+	}
+
+	p.started = true
+	p.prevTS = ev.TSNanos
+
+	return ev.Line, nil
+}
@@ -0,0 +1,99 @@
+package session
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRecordReplayRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+
+	rec := NewRecorder(&buf)
+	if err := rec.Record(`row one`); err != nil {
+		t.Fatalf(`Record: %v`, err)
+	}
+	if err := rec.Record(`row two`); err != nil {
+		t.Fatalf(`Record: %v`, err)
+	}
+
+	// Speed 0 is treated as 1 (real time); use a huge speed below instead
+	// of sleeping in the test.
+	rep := NewReplayer(&buf, 1e9)
+
+	line, err := rep.Next()
+	if err != nil {
+		t.Fatalf(`Next: %v`, err)
+	}
+	if line != `row one` {
+		t.Errorf(`first line = %q, want %q`, line, `row one`)
+	}
+
+	line, err = rep.Next()
+	if err != nil {
+		t.Fatalf(`Next: %v`, err)
+	}
+	if line != `row two` {
+		t.Errorf(`second line = %q, want %q`, line, `row two`)
+	}
+
+	if _, err := rep.Next(); err != io.EOF {
+		t.Fatalf(`Next at end = %v, want io.EOF`, err)
+	}
+}
+
+func TestNewReplayerZeroOrNegativeSpeedDefaultsToOne(t *testing.T) {
+	for _, speed := range []float64{0, -1} {
+		rep := NewReplayer(&bytes.Buffer{}, speed)
+		if rep.speed != 1 {
+			t.Errorf(`NewReplayer(speed=%v).speed = %v, want 1`, speed, rep.speed)
+		}
+	}
+}
+
+func TestReplayerScalesDelayBySpeed(t *testing.T) {
+	var buf bytes.Buffer
+
+	rec := NewRecorder(&buf)
+	if err := rec.Record(`first`); err != nil {
+		t.Fatalf(`Record: %v`, err)
+	}
+
+	// Fabricate a second event 100ms after the first, bypassing the real
+	// clock so the test doesn't depend on wall time passing during Record.
+	buf.WriteString(`{"ts_ns":100000000,"line":"second"}` + "\n")
+
+	rep := NewReplayer(&buf, 1)
+	if _, err := rep.Next(); err != nil {
+		t.Fatalf(`Next: %v`, err)
+	}
+
+	rep.prevTS = 0 // pin the delta to exactly 100ms regardless of Record's real timestamp
+
+	start := time.Now()
+	const fastSpeed = 1000.0
+	rep.speed = fastSpeed
+
+	line, err := rep.Next()
+	if err != nil {
+		t.Fatalf(`Next: %v`, err)
+	}
+	if line != `second` {
+		t.Fatalf(`line = %q, want %q`, line, `second`)
+	}
+
+	// 100ms scaled by a speed of 1000 should sleep ~100us: comfortably
+	// under a generous bound, so this doesn't flake under load.
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf(`Next took %v, want well under 50ms at speed=%v`, elapsed, fastSpeed)
+	}
+}
+
+func TestReplayerParseError(t *testing.T) {
+	rep := NewReplayer(bytes.NewBufferString("not json\n"), 1)
+
+	if _, err := rep.Next(); err == nil {
+		t.Fatal(`expected parse error, got nil`)
+	}
+}